@@ -0,0 +1,120 @@
+package manager
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kyma-incubator/hydroform/function/pkg/operator"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// node is one operator in the apply DAG, plus the edge to the operators
+// that must apply after it and inherit the owner references it produces.
+type node struct {
+	operator operator.Operator
+	children []*node
+
+	applied  bool
+	snapshot interface{}
+	refs     []metav1.OwnerReference
+}
+
+// dag is a manager's operator tree, built once from the
+// map[Operator][]Operator a caller passes to NewManager. Each root and its
+// children form a must-apply-before chain; roots are independent of one
+// another and of every other root's children.
+type dag struct {
+	roots []*node
+}
+
+func newDAG(operators map[operator.Operator][]operator.Operator) *dag {
+	d := &dag{}
+	for parent, children := range operators {
+		n := &node{operator: parent}
+		for _, child := range children {
+			if child == nil {
+				continue
+			}
+			n.children = append(n.children, &node{operator: child})
+		}
+		d.roots = append(d.roots, n)
+	}
+	return d
+}
+
+// reverseTopological lists every node children-before-parents, the order
+// rollback must run in so an owner-reference-from edge never outlives the
+// resource it points at.
+func (d *dag) reverseTopological() []*node {
+	var out []*node
+	for _, root := range d.roots {
+		out = append(out, root.children...)
+		out = append(out, root)
+	}
+	return out
+}
+
+// apply is called once per node, in root-then-children order, with the
+// owner references the node's parent produced (nil for roots).
+type apply func(ctx context.Context, n *node, parentRefs []metav1.OwnerReference) error
+
+// run walks the DAG, applying every root and its children. Roots are
+// scheduled across a worker pool sized by concurrency since they're
+// independent; a root and its children run sequentially, since the
+// children need the owner references the root produces. On the first
+// failure, ctx is cancelled so in-flight siblings stop at their next
+// context check and run returns that error.
+func (d *dag) run(ctx context.Context, concurrency int, do apply) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sem := make(chan struct{}, concurrency)
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	for _, root := range d.roots {
+		root := root
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := applySubtree(ctx, root, do); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+func applySubtree(ctx context.Context, root *node, do apply) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := do(ctx, root, nil); err != nil {
+		return err
+	}
+	for _, child := range root.children {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := do(ctx, child, root.refs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -14,49 +14,42 @@ type Manager interface {
 }
 
 type manager struct {
-	operators map[operator.Operator][]operator.Operator
+	dag *dag
 }
 
 func NewManager(operators map[operator.Operator][]operator.Operator) Manager {
 	return manager{
-		operators: operators,
+		dag: newDAG(operators),
 	}
 }
 
 func (m manager) Do(ctx context.Context, options Options) error {
-	err := m.manageOperators(ctx, options)
+	err := m.dag.run(ctx, options.Concurrency, func(ctx context.Context, n *node, parentRefs []metav1.OwnerReference) error {
+		return m.useNode(ctx, n, parentRefs, options)
+	})
 	if err != nil {
-		if options.OnError == PurgeOnError {
-			m.purgeParents(options)
-		}
+		m.rollback(options)
 		return err
 	}
 	return nil
 }
 
-func (m *manager) manageOperators(ctx context.Context, options Options) error {
-	for parent, children := range m.operators {
-		references, err := m.useOperator(ctx, parent, options, nil)
-		if err != nil {
-			return err
-		}
+func (m manager) useNode(ctx context.Context, n *node, references []metav1.OwnerReference, options Options) error {
+	if n == nil || n.operator == nil {
+		return nil
+	}
 
-		for _, resource := range children {
-			_, err := m.useOperator(ctx, resource, options, references)
+	if options.OnError == RollbackToSnapshot {
+		if snapshotter, ok := n.operator.(operator.Snapshotter); ok {
+			snapshot, err := snapshotter.Snapshot(ctx)
 			if err != nil {
 				return err
 			}
+			n.snapshot = snapshot
 		}
 	}
-	return nil
-}
 
-func (m *manager) useOperator(ctx context.Context, opr operator.Operator, options Options, references []metav1.OwnerReference) ([]metav1.OwnerReference, error) {
 	newRefs := OwnerReferenceList{}
-	if opr == nil {
-		return newRefs, nil
-	}
-
 	callbacks := options.Callbacks
 	if options.SetOwnerReferences {
 		callbacks = m.ownerReferenceCallback(options.Callbacks, &newRefs)
@@ -68,10 +61,31 @@ func (m *manager) useOperator(ctx context.Context, opr operator.Operator, option
 			Callbacks: callbacks,
 		},
 	}
-	return newRefs, opr.Apply(ctx, applyOpts)
+
+	if err := n.operator.Apply(ctx, applyOpts); err != nil {
+		return err
+	}
+	n.applied = true
+	n.refs = newRefs
+	return nil
+}
+
+// rollback runs the error policy selected by options.OnError once Do's DAG
+// walk has failed and every in-flight sibling has been cancelled.
+func (m manager) rollback(options Options) {
+	switch options.OnError {
+	case KeepOnError:
+	case RollbackToSnapshot:
+		m.rollbackToSnapshot(options)
+	default:
+		m.purgeApplied(options)
+	}
 }
 
-func (m *manager) purgeParents(options Options) {
+// purgeApplied deletes every node whose Apply actually produced a
+// resource, children before parents, so an owner-reference-from edge
+// never points at something already deleted.
+func (m manager) purgeApplied(options Options) {
 	deleteOptions := operator.DeleteOptions{
 		DeletionPropagation: metav1.DeletePropagationForeground,
 		Options: operator.Options{
@@ -80,15 +94,31 @@ func (m *manager) purgeParents(options Options) {
 		},
 	}
 
-	for opr := range m.operators {
-		if opr == nil {
+	for _, n := range m.dag.reverseTopological() {
+		if n.operator == nil || !n.applied {
+			continue
+		}
+		_ = n.operator.Delete(context.Background(), deleteOptions)
+	}
+}
+
+// rollbackToSnapshot restores every applied node that implements
+// operator.Snapshotter to the state captured right before its Apply ran.
+// Nodes without that capability are left as applied.
+func (m manager) rollbackToSnapshot(options Options) {
+	for _, n := range m.dag.reverseTopological() {
+		if n.operator == nil || !n.applied || n.snapshot == nil {
+			continue
+		}
+		snapshotter, ok := n.operator.(operator.Snapshotter)
+		if !ok {
 			continue
 		}
-		_ = opr.Delete(context.Background(), deleteOptions)
+		_ = snapshotter.Restore(context.Background(), n.snapshot)
 	}
 }
 
-func (m *manager) getDryRunFlag(dryRun bool) []string {
+func (m manager) getDryRunFlag(dryRun bool) []string {
 	var flags []string
 	if dryRun {
 		flags = append(flags, metav1.DryRunAll)
@@ -98,7 +128,7 @@ func (m *manager) getDryRunFlag(dryRun bool) []string {
 
 type OwnerReferenceList []metav1.OwnerReference
 
-func (m *manager) ownerReferenceCallback(callbacks operator.Callbacks, list *OwnerReferenceList) operator.Callbacks {
+func (m manager) ownerReferenceCallback(callbacks operator.Callbacks, list *OwnerReferenceList) operator.Callbacks {
 	if list == nil {
 		return callbacks
 	}
@@ -0,0 +1,217 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/function/pkg/operator"
+	"github.com/pkg/errors"
+)
+
+// fakeOperator is a minimal operator.Operator used to drive manager.Do in
+// tests without a real cluster client.
+type fakeOperator struct {
+	mu       sync.Mutex
+	applyErr error
+	delay    time.Duration
+	applied  bool
+	deleted  bool
+
+	// inFlight/peak let a test observe how many fakeOperators were applying
+	// at once, to assert the DAG scheduler actually parallelizes roots.
+	inFlight *int32
+	peak     *int32
+}
+
+func (f *fakeOperator) Apply(ctx context.Context, opts operator.ApplyOptions) error {
+	if f.inFlight != nil {
+		n := atomic.AddInt32(f.inFlight, 1)
+		defer atomic.AddInt32(f.inFlight, -1)
+		for {
+			p := atomic.LoadInt32(f.peak)
+			if n <= p || atomic.CompareAndSwapInt32(f.peak, p, n) {
+				break
+			}
+		}
+	}
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.applyErr != nil {
+		return f.applyErr
+	}
+	f.applied = true
+	return nil
+}
+
+func (f *fakeOperator) Delete(ctx context.Context, opts operator.DeleteOptions) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = true
+	return nil
+}
+
+func (f *fakeOperator) wasApplied() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.applied
+}
+
+func (f *fakeOperator) wasDeleted() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.deleted
+}
+
+// snapshotOperator is a fakeOperator that also implements
+// operator.Snapshotter, for exercising RollbackToSnapshot. existed models
+// whether Snapshot found prior state to capture: false is the common case
+// of a resource's first-ever Apply, where there's nothing to restore and
+// Restore should undo the create instead of reapplying a prior version
+// that never existed.
+type snapshotOperator struct {
+	fakeOperator
+	existed          bool
+	restored         bool
+	deletedOnRestore bool
+}
+
+type fakeSnapshot struct {
+	existed bool
+}
+
+func (s *snapshotOperator) Snapshot(ctx context.Context) (interface{}, error) {
+	return fakeSnapshot{existed: s.existed}, nil
+}
+
+func (s *snapshotOperator) Restore(ctx context.Context, snapshot interface{}) error {
+	snap, ok := snapshot.(fakeSnapshot)
+	if !ok {
+		return errors.Errorf("unexpected snapshot type %T", snapshot)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if snap.existed {
+		s.restored = true
+	} else {
+		s.deletedOnRestore = true
+	}
+	return nil
+}
+
+func Test_manager_Do_parallelizesIndependentRoots(t *testing.T) {
+	var inFlight, peak int32
+
+	roots := map[operator.Operator][]operator.Operator{}
+	for i := 0; i < 4; i++ {
+		roots[&fakeOperator{delay: 20 * time.Millisecond, inFlight: &inFlight, peak: &peak}] = nil
+	}
+
+	mgr := NewManager(roots)
+	if err := mgr.Do(context.Background(), Options{Concurrency: 4}); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	if atomic.LoadInt32(&peak) < 2 {
+		t.Errorf("peak concurrent Apply() calls = %d, want >= 2 with Concurrency: 4", peak)
+	}
+}
+
+func Test_manager_Do_appliesChildrenAfterParent(t *testing.T) {
+	child := &fakeOperator{}
+	parent := &fakeOperator{}
+
+	mgr := NewManager(map[operator.Operator][]operator.Operator{
+		parent: {child},
+	})
+	if err := mgr.Do(context.Background(), Options{}); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if !parent.wasApplied() || !child.wasApplied() {
+		t.Error("Do() did not apply both parent and child")
+	}
+}
+
+func Test_manager_Do_rollback(t *testing.T) {
+	applyErr := errors.New("boom")
+
+	tests := []struct {
+		name           string
+		onError        OnError
+		wantOkDeleted  bool
+		wantOkRestored bool
+	}{
+		{name: "purge on error deletes the applied sibling", onError: PurgeOnError, wantOkDeleted: true},
+		{name: "keep on error leaves the applied sibling alone", onError: KeepOnError, wantOkDeleted: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Both roots start together and failing errors only after its
+			// delay, so ok is always applied before the walk is cancelled.
+			ok := &fakeOperator{}
+			failing := &fakeOperator{applyErr: applyErr, delay: 20 * time.Millisecond}
+
+			mgr := NewManager(map[operator.Operator][]operator.Operator{
+				ok:      nil,
+				failing: nil,
+			})
+			err := mgr.Do(context.Background(), Options{OnError: tt.onError, Concurrency: 2})
+			if err == nil {
+				t.Fatal("Do() error = nil, want the failing operator's error")
+			}
+			if got := ok.wasDeleted(); got != tt.wantOkDeleted {
+				t.Errorf("ok.wasDeleted() = %v, want %v", got, tt.wantOkDeleted)
+			}
+		})
+	}
+}
+
+func Test_manager_Do_rollbackToSnapshot(t *testing.T) {
+	ok := &snapshotOperator{existed: true}
+	failing := &fakeOperator{applyErr: errors.New("boom"), delay: 20 * time.Millisecond}
+
+	mgr := NewManager(map[operator.Operator][]operator.Operator{
+		ok:      nil,
+		failing: nil,
+	})
+	err := mgr.Do(context.Background(), Options{OnError: RollbackToSnapshot, Concurrency: 2})
+	if err == nil {
+		t.Fatal("Do() error = nil, want the failing operator's error")
+	}
+	if ok.wasDeleted() {
+		t.Error("RollbackToSnapshot deleted a Snapshotter node instead of restoring it")
+	}
+	if !ok.restored {
+		t.Error("RollbackToSnapshot did not call Restore on a node implementing operator.Snapshotter")
+	}
+}
+
+// Test_manager_Do_rollbackToSnapshot_notYetExisting covers a node applied
+// for the first time: Snapshot has nothing to restore it to, so Restore
+// must undo the create rather than reapply a prior version that never
+// existed.
+func Test_manager_Do_rollbackToSnapshot_notYetExisting(t *testing.T) {
+	ok := &snapshotOperator{existed: false}
+	failing := &fakeOperator{applyErr: errors.New("boom"), delay: 20 * time.Millisecond}
+
+	mgr := NewManager(map[operator.Operator][]operator.Operator{
+		ok:      nil,
+		failing: nil,
+	})
+	err := mgr.Do(context.Background(), Options{OnError: RollbackToSnapshot, Concurrency: 2})
+	if err == nil {
+		t.Fatal("Do() error = nil, want the failing operator's error")
+	}
+	if ok.restored {
+		t.Error("RollbackToSnapshot restored a node that had no prior state to restore")
+	}
+	if !ok.deletedOnRestore {
+		t.Error("RollbackToSnapshot did not undo the create for a node with no prior state")
+	}
+}
@@ -0,0 +1,32 @@
+package manager
+
+import "github.com/kyma-incubator/hydroform/function/pkg/operator"
+
+// OnError selects how Do reacts when a node in the operator DAG fails to apply.
+type OnError int
+
+const (
+	// PurgeOnError deletes every resource that was successfully applied
+	// before the failure. This is the default.
+	PurgeOnError OnError = iota
+	// KeepOnError leaves whatever was already applied in place and just
+	// returns the error, for callers that want to inspect partial state.
+	KeepOnError
+	// RollbackToSnapshot restores every applied node to the state it had
+	// right before Apply ran, using operator.Snapshotter, instead of
+	// deleting it. Nodes that don't implement operator.Snapshotter are
+	// left as applied.
+	RollbackToSnapshot
+)
+
+// Options configures a single Manager.Do run.
+type Options struct {
+	OnError            OnError
+	DryRun             bool
+	SetOwnerReferences bool
+	Callbacks          operator.Callbacks
+
+	// Concurrency bounds how many independent DAG subtrees Do applies at
+	// once. Values below 1 are treated as 1 (fully sequential).
+	Concurrency int
+}
@@ -0,0 +1,23 @@
+package operator
+
+import (
+	"github.com/kyma-incubator/hydroform/function/pkg/client"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// NewFunctionOperators assembles the flat operator DAG manager.Do applies
+// for a single Function: triggersOperator for the attached Trigger CRs,
+// and, when the function's source is a pre-pushed OCI artifact,
+// ociOperator to keep the Function CR's source spec in sync with it.
+// Both are independent roots, since neither's Apply depends on the other
+// completing first. It's the typical body of the server.OperatorBuilder a
+// caller passes to server.New.
+func NewFunctionOperators(c client.Client, triggers []unstructured.Unstructured, ociSource *unstructured.Unstructured) map[Operator][]Operator {
+	operators := map[Operator][]Operator{
+		NewTriggersOperator(c, triggers...): nil,
+	}
+	if ociSource != nil {
+		operators[NewOCIOperator(c, *ociSource)] = nil
+	}
+	return operators
+}
@@ -0,0 +1,13 @@
+package operator
+
+import "context"
+
+// Snapshotter is an optional capability an Operator can implement to
+// support the manager's RollbackToSnapshot error policy: Snapshot captures
+// whatever state is needed to undo an Apply, and Restore puts it back.
+// Operators that don't implement it are simply left as applied (or purged,
+// under PurgeOnError) on failure.
+type Snapshotter interface {
+	Snapshot(ctx context.Context) (interface{}, error)
+	Restore(ctx context.Context, snapshot interface{}) error
+}
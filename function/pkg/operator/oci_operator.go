@@ -0,0 +1,62 @@
+package operator
+
+import (
+	"context"
+
+	"github.com/kyma-incubator/hydroform/function/pkg/client"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ociOperator materializes a pre-pushed OCI artifact reference into a
+// Function CR's source spec during Apply, the same way triggersOperator
+// materializes trigger CRs: it owns one unstructured object, labels it
+// with the owning Function's UID, and routes Apply/Delete through the
+// pre/post callback chain.
+type ociOperator struct {
+	item unstructured.Unstructured
+	client.Client
+}
+
+func NewOCIOperator(c client.Client, u unstructured.Unstructured) Operator {
+	return &ociOperator{
+		Client: c,
+		item:   u,
+	}
+}
+
+func (o ociOperator) Apply(ctx context.Context, opts ApplyOptions) error {
+	ownerID, found := findOwnerID(opts.OwnerReferences)
+	if !found {
+		return errors.Wrap(errNotFound, message)
+	}
+
+	u := o.item
+	u.SetOwnerReferences(opts.OwnerReferences)
+	newLabels := mergeMap(u.GetLabels(), map[string]string{
+		message: ownerID,
+	})
+	u.SetLabels(newLabels)
+
+	// fire pre callbacks
+	if err := fireCallbacks(&u, nil, opts.Pre...); err != nil {
+		return err
+	}
+	new1, statusEntry, err := applyObject(ctx, o.Client, u, opts.DryRun)
+	// fire post callbacks
+	if err := fireCallbacks(statusEntry, err, opts.Post...); err != nil {
+		return err
+	}
+	o.item.SetUnstructuredContent(new1.Object)
+	return nil
+}
+
+func (o ociOperator) Delete(ctx context.Context, opts DeleteOptions) error {
+	// fire pre callbacks
+	if err := fireCallbacks(&o.item, nil, opts.Pre...); err != nil {
+		return err
+	}
+	state, err := deleteObject(ctx, o.Client, o.item, opts)
+	// fire post callbacks
+	return fireCallbacks(state, err, opts.Post...)
+}
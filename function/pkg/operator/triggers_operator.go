@@ -5,6 +5,7 @@ import (
 
 	"github.com/kyma-incubator/hydroform/function/pkg/client"
 	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -54,6 +55,59 @@ func (t triggersOperator) Apply(ctx context.Context, opts ApplyOptions) error {
 	return nil
 }
 
+// triggerSnapshot records a single trigger's state before Apply ran.
+// existed is false when the trigger had no prior state to capture (the
+// normal case on a Function's first-ever apply) — Restore needs that to
+// tell "put the old version back" apart from "this was never there, undo
+// the create".
+type triggerSnapshot struct {
+	object  unstructured.Unstructured
+	existed bool
+}
+
+// Snapshot implements Snapshotter: it reads back the live state of every
+// trigger before Apply overwrites it, so a RollbackToSnapshot policy can
+// put the cluster back the way it found it. A trigger that doesn't exist
+// yet isn't an error here — it just means Restore should delete it rather
+// than re-apply a prior version that never existed.
+func (t triggersOperator) Snapshot(ctx context.Context) (interface{}, error) {
+	prev := make([]triggerSnapshot, 0, len(t.items))
+	for _, u := range t.items {
+		existing, err := getObject(ctx, t.Client, u)
+		if apierrors.IsNotFound(err) {
+			prev = append(prev, triggerSnapshot{object: u})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		prev = append(prev, triggerSnapshot{object: existing, existed: true})
+	}
+	return prev, nil
+}
+
+// Restore implements Snapshotter: it puts every trigger back the way
+// Snapshot found it, re-applying the ones that already existed and
+// deleting the ones Apply created from nothing.
+func (t triggersOperator) Restore(ctx context.Context, snapshot interface{}) error {
+	prev, ok := snapshot.([]triggerSnapshot)
+	if !ok {
+		return errors.Errorf("triggersOperator: unexpected snapshot type %T", snapshot)
+	}
+	for _, s := range prev {
+		if !s.existed {
+			if _, err := deleteObject(ctx, t.Client, s.object, DeleteOptions{DeletionPropagation: v1.DeletePropagationForeground}); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, _, err := applyObject(ctx, t.Client, s.object, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (t triggersOperator) Delete(ctx context.Context, opts DeleteOptions) error {
 	for _, u := range t.items {
 		// fire pre callbacks
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kyma-incubator/hydroform/function/pkg/client"
+	"github.com/kyma-incubator/hydroform/function/pkg/manager"
+	"github.com/kyma-incubator/hydroform/function/pkg/operator"
+	"github.com/kyma-incubator/hydroform/function/pkg/workspace"
+	"github.com/pkg/errors"
+)
+
+// requestIDHeader names the header clients set to make an :apply call
+// resumable: Store.Get(requestID) replays the Operation recorded so far
+// after a disconnect, instead of the client re-submitting the apply.
+const requestIDHeader = "X-Request-Id"
+
+// handleApply implements POST /v1/functions/{ns}/{name}:apply: it drives
+// manager.Do for the function's operator DAG and streams every
+// client.PostStatusEntry as it passes through the post-callback chain
+// over SSE, persisting the same entries to Store so a disconnected client
+// can resume by polling GET .../:apply with the same X-Request-Id header.
+func (s *Server) handleApply(w http.ResponseWriter, r *http.Request, path string) {
+	namespace, name, ok := splitNamespacedName(path)
+	if !ok {
+		http.Error(w, "expected /v1/functions/{namespace}/{name}:apply", http.StatusBadRequest)
+		return
+	}
+
+	requestID := r.Header.Get(requestIDHeader)
+	if requestID == "" {
+		http.Error(w, requestIDHeader+" header is required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Cfg    workspace.Cfg `json:"cfg"`
+		DryRun bool          `json:"dryRun"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, errors.Wrap(err, "decoding request").Error(), http.StatusBadRequest)
+		return
+	}
+	req.Cfg.Namespace = namespace
+	req.Cfg.Name = name
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	op := Operation{
+		RequestID: requestID,
+		Namespace: namespace,
+		Name:      name,
+		Status:    OperationRunning,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.store.Create(r.Context(), op); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	sw := bufio.NewWriter(w)
+
+	// manager.Do (since chunk0-2) applies independent DAG subtrees across a
+	// worker pool, so this callback can run concurrently from more than one
+	// of those goroutines. op.Entries, the Store write and sw/flusher are
+	// all shared mutable state, so every access here needs streamMu held.
+	var streamMu sync.Mutex
+	options := manager.Options{
+		OnError:            manager.PurgeOnError,
+		DryRun:             req.DryRun,
+		SetOwnerReferences: true,
+		Callbacks: operator.Callbacks{
+			Post: []func(interface{}, error) error{
+				func(v interface{}, err error) error {
+					entry, ok := v.(client.PostStatusEntry)
+					if ok {
+						streamMu.Lock()
+						op.Entries = append(op.Entries, entry)
+						op.UpdatedAt = time.Now()
+						_ = s.store.Update(r.Context(), op)
+						writeSSE(sw, entry)
+						flusher.Flush()
+						streamMu.Unlock()
+					}
+					return err
+				},
+			},
+		},
+	}
+
+	mgr := manager.NewManager(s.buildOperators(req.Cfg))
+	err := mgr.Do(r.Context(), options)
+
+	op.UpdatedAt = time.Now()
+	if err != nil {
+		op.Status = OperationFailed
+		op.Error = err.Error()
+	} else {
+		op.Status = OperationSucceeded
+	}
+	_ = s.store.Update(r.Context(), op)
+}
+
+// handlePollApply implements GET /v1/functions/{ns}/{name}:apply: it
+// replays the Operation recorded so far for the request ID a client
+// disconnected under, instead of re-driving manager.Do.
+func (s *Server) handlePollApply(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Header.Get(requestIDHeader)
+	if requestID == "" {
+		http.Error(w, requestIDHeader+" header is required", http.StatusBadRequest)
+		return
+	}
+
+	op, err := s.store.Get(r.Context(), requestID)
+	if errors.Is(err, ErrOperationNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(op)
+}
+
+func writeSSE(w *bufio.Writer, entry client.PostStatusEntry) {
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "data: %s\n\n", payload)
+	_ = w.Flush()
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/function/pkg/operator"
+	"github.com/kyma-incubator/hydroform/function/pkg/workspace"
+	"github.com/pkg/errors"
+)
+
+// fakeOperator is a minimal operator.Operator double so handleApply's
+// tests can drive manager.Do without a real cluster client.
+type fakeOperator struct {
+	applyErr error
+}
+
+func (f *fakeOperator) Apply(ctx context.Context, opts operator.ApplyOptions) error {
+	return f.applyErr
+}
+
+func (f *fakeOperator) Delete(ctx context.Context, opts operator.DeleteOptions) error {
+	return nil
+}
+
+func newTestServer(root operator.Operator) *Server {
+	return New(nil, func(cfg workspace.Cfg) map[operator.Operator][]operator.Operator {
+		return map[operator.Operator][]operator.Operator{root: nil}
+	}, NewMemoryStore())
+}
+
+func Test_handleApply_missingRequestIDHeader(t *testing.T) {
+	s := newTestServer(&fakeOperator{})
+	req := httptest.NewRequest(http.MethodPost, "/v1/functions/test-ns/test-fn:apply", bytes.NewReader([]byte(`{}`)))
+	rec := httptest.NewRecorder()
+
+	s.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func Test_handleApply_malformedBody(t *testing.T) {
+	s := newTestServer(&fakeOperator{})
+	req := httptest.NewRequest(http.MethodPost, "/v1/functions/test-ns/test-fn:apply", bytes.NewReader([]byte("not json")))
+	req.Header.Set(requestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+
+	s.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+// Test_handleApply_resumeAfterDisconnect drives an :apply call through to
+// completion, then replays the same request ID against the poll endpoint
+// the way a client that disconnected mid-stream would, and checks it gets
+// back the same recorded Operation instead of needing to resubmit.
+func Test_handleApply_resumeAfterDisconnect(t *testing.T) {
+	s := newTestServer(&fakeOperator{})
+
+	body, err := json.Marshal(map[string]interface{}{"cfg": workspace.Cfg{}})
+	if err != nil {
+		t.Fatalf("marshalling request body: %v", err)
+	}
+	applyReq := httptest.NewRequest(http.MethodPost, "/v1/functions/test-ns/test-fn:apply", bytes.NewReader(body))
+	applyReq.Header.Set(requestIDHeader, "req-1")
+	applyRec := httptest.NewRecorder()
+
+	s.ServeHTTP(applyRec, applyReq)
+
+	if applyRec.Code != http.StatusOK {
+		t.Fatalf("apply status = %d, body = %s", applyRec.Code, applyRec.Body.String())
+	}
+
+	pollReq := httptest.NewRequest(http.MethodGet, "/v1/functions/test-ns/test-fn:apply", nil)
+	pollReq.Header.Set(requestIDHeader, "req-1")
+	pollRec := httptest.NewRecorder()
+
+	s.ServeHTTP(pollRec, pollReq)
+
+	if pollRec.Code != http.StatusOK {
+		t.Fatalf("poll status = %d, body = %s", pollRec.Code, pollRec.Body.String())
+	}
+	var op Operation
+	if err := json.Unmarshal(pollRec.Body.Bytes(), &op); err != nil {
+		t.Fatalf("decoding polled operation: %v", err)
+	}
+	if op.RequestID != "req-1" {
+		t.Errorf("op.RequestID = %v, want req-1", op.RequestID)
+	}
+	if op.Status != OperationSucceeded {
+		t.Errorf("op.Status = %v, want %v", op.Status, OperationSucceeded)
+	}
+}
+
+// Test_handleApply_failureIsPolledBack covers the same resume path, but
+// for an apply whose operator DAG failed: the poll response should carry
+// the failure, not a 500 from the poll endpoint itself.
+func Test_handleApply_failureIsPolledBack(t *testing.T) {
+	s := newTestServer(&fakeOperator{applyErr: errors.New("boom")})
+
+	body, _ := json.Marshal(map[string]interface{}{"cfg": workspace.Cfg{}})
+	applyReq := httptest.NewRequest(http.MethodPost, "/v1/functions/test-ns/test-fn:apply", bytes.NewReader(body))
+	applyReq.Header.Set(requestIDHeader, "req-2")
+	applyRec := httptest.NewRecorder()
+	s.ServeHTTP(applyRec, applyReq)
+
+	pollReq := httptest.NewRequest(http.MethodGet, "/v1/functions/test-ns/test-fn:apply", nil)
+	pollReq.Header.Set(requestIDHeader, "req-2")
+	pollRec := httptest.NewRecorder()
+	s.ServeHTTP(pollRec, pollReq)
+
+	if pollRec.Code != http.StatusOK {
+		t.Fatalf("poll status = %d, body = %s", pollRec.Code, pollRec.Body.String())
+	}
+	var op Operation
+	if err := json.Unmarshal(pollRec.Body.Bytes(), &op); err != nil {
+		t.Fatalf("decoding polled operation: %v", err)
+	}
+	if op.Status != OperationFailed {
+		t.Errorf("op.Status = %v, want %v", op.Status, OperationFailed)
+	}
+	if op.Error == "" {
+		t.Error("op.Error is empty, want the failing operator's error")
+	}
+}
+
+func Test_handlePollApply_unknownRequestID(t *testing.T) {
+	s := newTestServer(&fakeOperator{})
+	req := httptest.NewRequest(http.MethodGet, "/v1/functions/test-ns/test-fn:apply", nil)
+	req.Header.Set(requestIDHeader, "never-submitted")
+	rec := httptest.NewRecorder()
+
+	s.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/kyma-incubator/hydroform/function/pkg/workspace"
+	"github.com/pkg/errors"
+)
+
+// handleInitialize implements POST /v1/functions: it renders cfg into a
+// fresh workspace via workspace.Initialize and returns it as a zip.
+func (s *Server) handleInitialize(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Cfg workspace.Cfg `json:"cfg"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, errors.Wrap(err, "decoding request").Error(), http.StatusBadRequest)
+		return
+	}
+
+	files := newZipWriterProvider()
+	if err := workspace.Initialize(req.Cfg, "", files.writerProvider()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	if err := files.writeTo(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/function/pkg/resources/types"
+	"github.com/kyma-incubator/hydroform/function/pkg/workspace"
+)
+
+func newInitializeRequest(t *testing.T, cfg workspace.Cfg) *http.Request {
+	t.Helper()
+	body, err := json.Marshal(map[string]interface{}{"cfg": cfg})
+	if err != nil {
+		t.Fatalf("marshalling request body: %v", err)
+	}
+	return httptest.NewRequest(http.MethodPost, "/v1/functions", bytes.NewReader(body))
+}
+
+func Test_handleInitialize_malformedBody(t *testing.T) {
+	s := New(nil, nil, NewMemoryStore())
+	req := httptest.NewRequest(http.MethodPost, "/v1/functions", bytes.NewReader([]byte("not json")))
+	rec := httptest.NewRecorder()
+
+	s.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func Test_handleInitialize_methodNotAllowed(t *testing.T) {
+	s := New(nil, nil, NewMemoryStore())
+	req := httptest.NewRequest(http.MethodGet, "/v1/functions", nil)
+	rec := httptest.NewRecorder()
+
+	s.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func Test_handleInitialize_happyPath(t *testing.T) {
+	s := New(nil, nil, NewMemoryStore())
+
+	req := newInitializeRequest(t, workspace.Cfg{
+		Runtime: types.Python38,
+		Triggers: []workspace.Trigger{
+			{Version: "test-version", Source: "test-source", Type: "test-type"},
+		},
+	})
+	rec := httptest.NewRecorder()
+
+	s.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/zip" {
+		t.Errorf("Content-Type = %q, want application/zip", ct)
+	}
+	if _, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len())); err != nil {
+		t.Errorf("response body is not a valid zip archive: %v", err)
+	}
+}
+
+func Test_handleInitialize_unsupportedRuntime(t *testing.T) {
+	s := New(nil, nil, NewMemoryStore())
+
+	req := newInitializeRequest(t, workspace.Cfg{Runtime: types.Runtime("unsupported runtime")})
+	rec := httptest.NewRecorder()
+
+	s.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,88 @@
+// Package server exposes workspace.Synchronise, workspace.Initialize and
+// manager.Do over a versioned HTTP API, so hydroform can run as a
+// controller-style daemon that CI systems and UIs share instead of each
+// re-implementing the sync/apply plumbing as a library.
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/kyma-incubator/hydroform/function/pkg/client"
+	"github.com/kyma-incubator/hydroform/function/pkg/operator"
+	"github.com/kyma-incubator/hydroform/function/pkg/workspace"
+)
+
+// OperatorBuilder builds the operator DAG manager.Do should apply for a
+// given function, resolved per request since each apply targets a
+// different namespace/name. operator.NewFunctionOperators is the typical
+// implementation.
+type OperatorBuilder func(cfg workspace.Cfg) map[operator.Operator][]operator.Operator
+
+// Server wires the workspace and manager packages behind net/http. It
+// holds no cluster credentials itself; build and buildOperators are
+// supplied by the caller the same way they already are to synchronise
+// and NewManager.
+type Server struct {
+	mux            *http.ServeMux
+	build          client.Build
+	buildOperators OperatorBuilder
+	store          Store
+}
+
+// New builds a Server. build resolves a client.Client for POST
+// .../:sync, buildOperators resolves the operator DAG manager.Do applies
+// for POST .../:apply (a fresh manager.Manager is built from it per
+// request, since every apply targets a different function), and store
+// records operation history so clients can resume polling after a
+// disconnect.
+func New(build client.Build, buildOperators OperatorBuilder, store Store) *Server {
+	s := &Server{
+		mux:            http.NewServeMux(),
+		build:          build,
+		buildOperators: buildOperators,
+		store:          store,
+	}
+	s.mux.HandleFunc("/v1/functions", s.handleInitialize)
+	s.mux.HandleFunc("/v1/functions/", s.handleFunctionAction)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handleFunctionAction dispatches the path-suffixed actions on a
+// namespaced function: POST /v1/functions/{ns}/{name}:sync,
+// POST /v1/functions/{ns}/{name}:apply and the GET .../:apply poll.
+func (s *Server) handleFunctionAction(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v1/functions/")
+
+	if r.Method == http.MethodGet && strings.HasSuffix(path, ":apply") {
+		s.handlePollApply(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case strings.HasSuffix(path, ":sync"):
+		s.handleSync(w, r, strings.TrimSuffix(path, ":sync"))
+	case strings.HasSuffix(path, ":apply"):
+		s.handleApply(w, r, strings.TrimSuffix(path, ":apply"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// splitNamespacedName parses the "{ns}/{name}" segment shared by :sync
+// and :apply.
+func splitNamespacedName(path string) (namespace, name string, ok bool) {
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrOperationNotFound is returned by Store.Get when no operation is
+// recorded under the given request ID.
+var ErrOperationNotFound = errors.New("operation not found")
+
+// Store persists Operation history keyed by request ID, so a client that
+// disconnects mid apply can resume polling instead of re-submitting.
+// NewMemoryStore and NewSQLStore (SQLite or Postgres, via database/sql)
+// both implement it.
+type Store interface {
+	Create(ctx context.Context, op Operation) error
+	Update(ctx context.Context, op Operation) error
+	Get(ctx context.Context, requestID string) (Operation, error)
+}
+
+// memoryStore is the default Store: an in-process map, lost on restart.
+type memoryStore struct {
+	mu  sync.RWMutex
+	ops map[string]Operation
+}
+
+// NewMemoryStore returns a Store that keeps operation history in memory
+// only. Good enough for local use and CI; anything that needs operation
+// history to survive a restart should use NewSQLStore instead.
+func NewMemoryStore() Store {
+	return &memoryStore{ops: map[string]Operation{}}
+}
+
+func (s *memoryStore) Create(_ context.Context, op Operation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ops[op.RequestID] = op
+	return nil
+}
+
+func (s *memoryStore) Update(ctx context.Context, op Operation) error {
+	return s.Create(ctx, op)
+}
+
+func (s *memoryStore) Get(_ context.Context, requestID string) (Operation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	op, ok := s.ops[requestID]
+	if !ok {
+		return Operation{}, errors.Wrap(ErrOperationNotFound, requestID)
+	}
+	return op, nil
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Dialect selects the placeholder syntax sqlStore rebinds its queries to.
+// SQLite (and MySQL) accept positional "?" placeholders; Postgres
+// requires "$1, $2, ...".
+type Dialect string
+
+const (
+	DialectSQLite   Dialect = "sqlite"
+	DialectPostgres Dialect = "postgres"
+)
+
+// sqlStore is a Store backed by database/sql, usable for both SQLite and
+// Postgres: callers pick the dialect by importing the matching driver
+// (e.g. mattn/go-sqlite3 or lib/pq), opening db with it, and passing the
+// matching Dialect to NewSQLStore so queries are rebound correctly.
+type sqlStore struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewSQLStore wraps an already-open *sql.DB as a Store. db is expected to
+// have the `operations` table from schema.sql.
+func NewSQLStore(db *sql.DB, dialect Dialect) Store {
+	return &sqlStore{db: db, dialect: dialect}
+}
+
+// rebind rewrites a query written with "?" placeholders into the syntax
+// dialect expects. SQLite queries pass through unchanged.
+func (s *sqlStore) rebind(query string) string {
+	if s.dialect != DialectPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteByte('$')
+		b.WriteString(strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+func (s *sqlStore) Create(ctx context.Context, op Operation) error {
+	entries, err := json.Marshal(op.Entries)
+	if err != nil {
+		return errors.Wrap(err, "marshalling operation entries")
+	}
+	_, err = s.db.ExecContext(ctx,
+		s.rebind(`INSERT INTO operations (request_id, namespace, name, status, entries, error, created_at, updated_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`),
+		op.RequestID, op.Namespace, op.Name, op.Status, entries, op.Error, op.CreatedAt, op.UpdatedAt)
+	return errors.Wrap(err, "inserting operation")
+}
+
+func (s *sqlStore) Update(ctx context.Context, op Operation) error {
+	entries, err := json.Marshal(op.Entries)
+	if err != nil {
+		return errors.Wrap(err, "marshalling operation entries")
+	}
+	_, err = s.db.ExecContext(ctx,
+		s.rebind(`UPDATE operations SET status = ?, entries = ?, error = ?, updated_at = ? WHERE request_id = ?`),
+		op.Status, entries, op.Error, op.UpdatedAt, op.RequestID)
+	return errors.Wrap(err, "updating operation")
+}
+
+func (s *sqlStore) Get(ctx context.Context, requestID string) (Operation, error) {
+	row := s.db.QueryRowContext(ctx,
+		s.rebind(`SELECT request_id, namespace, name, status, entries, error, created_at, updated_at
+			 FROM operations WHERE request_id = ?`), requestID)
+
+	var (
+		op      Operation
+		entries []byte
+	)
+	err := row.Scan(&op.RequestID, &op.Namespace, &op.Name, &op.Status, &entries, &op.Error, &op.CreatedAt, &op.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Operation{}, errors.Wrap(ErrOperationNotFound, requestID)
+	}
+	if err != nil {
+		return Operation{}, errors.Wrap(err, "reading operation")
+	}
+	if err := json.Unmarshal(entries, &op.Entries); err != nil {
+		return Operation{}, errors.Wrap(err, "unmarshalling operation entries")
+	}
+	return op, nil
+}
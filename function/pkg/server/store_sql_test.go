@@ -0,0 +1,32 @@
+package server
+
+import "testing"
+
+func Test_sqlStore_rebind(t *testing.T) {
+	query := `UPDATE operations SET status = ?, entries = ? WHERE request_id = ?`
+
+	tests := []struct {
+		name    string
+		dialect Dialect
+		want    string
+	}{
+		{
+			name:    "sqlite passes through",
+			dialect: DialectSQLite,
+			want:    query,
+		},
+		{
+			name:    "postgres numbers placeholders",
+			dialect: DialectPostgres,
+			want:    `UPDATE operations SET status = $1, entries = $2 WHERE request_id = $3`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &sqlStore{dialect: tt.dialect}
+			if got := s.rebind(query); got != tt.want {
+				t.Errorf("rebind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_memoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if _, err := store.Get(ctx, "missing"); err == nil {
+		t.Error("Get() on missing request id: error = nil, want error")
+	}
+
+	op := Operation{RequestID: "req-1", Namespace: "ns", Name: "fn", Status: OperationRunning, CreatedAt: time.Unix(0, 0)}
+	if err := store.Create(ctx, op); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	got, err := store.Get(ctx, "req-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status != OperationRunning {
+		t.Errorf("Get() status = %v, want %v", got.Status, OperationRunning)
+	}
+
+	op.Status = OperationSucceeded
+	if err := store.Update(ctx, op); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	got, err = store.Get(ctx, "req-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status != OperationSucceeded {
+		t.Errorf("Get() after Update() status = %v, want %v", got.Status, OperationSucceeded)
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/kyma-incubator/hydroform/function/pkg/workspace"
+	"github.com/pkg/errors"
+)
+
+// handleSync implements POST /v1/functions/{ns}/{name}:sync: it pulls the
+// Function's current spec and triggers from the cluster via
+// workspace.Synchronise and streams the rendered workspace back as a zip.
+func (s *Server) handleSync(w http.ResponseWriter, r *http.Request, path string) {
+	namespace, name, ok := splitNamespacedName(path)
+	if !ok {
+		http.Error(w, "expected /v1/functions/{namespace}/{name}:sync", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Cfg workspace.Cfg `json:"cfg"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, errors.Wrap(err, "decoding request").Error(), http.StatusBadRequest)
+		return
+	}
+	req.Cfg.Namespace = namespace
+	req.Cfg.Name = name
+
+	files := newZipWriterProvider()
+	if err := workspace.Synchronise(r.Context(), req.Cfg, "", s.build, files.writerProvider()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	if err := files.writeTo(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// zipWriterProvider implements workspace.WriterProvider by buffering every
+// written file in memory and flushing them into a zip archive once the
+// workspace render is done.
+type zipWriterProvider struct {
+	files map[string][]byte
+}
+
+func newZipWriterProvider() *zipWriterProvider {
+	return &zipWriterProvider{files: map[string][]byte{}}
+}
+
+func (z *zipWriterProvider) writerProvider() workspace.WriterProvider {
+	return func(path string) (io.Writer, workspace.Cancel, error) {
+		buf := &memFile{}
+		z.files[path] = nil
+		return buf, func() error {
+			z.files[path] = buf.Bytes()
+			return nil
+		}, nil
+	}
+}
+
+func (z *zipWriterProvider) writeTo(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	for path, content := range z.files {
+		entry, err := zw.Create(path)
+		if err != nil {
+			return errors.Wrapf(err, "adding %s to archive", path)
+		}
+		if _, err := entry.Write(content); err != nil {
+			return errors.Wrapf(err, "writing %s to archive", path)
+		}
+	}
+	return zw.Close()
+}
+
+// memFile is a growable byte buffer satisfying io.Writer, used as the
+// in-memory sink workspace.WriterProvider writes each file to before it's
+// packed into the zip.
+type memFile struct {
+	buf []byte
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	f.buf = append(f.buf, p...)
+	return len(p), nil
+}
+
+func (f *memFile) Bytes() []byte {
+	return f.buf
+}
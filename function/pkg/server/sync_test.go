@@ -0,0 +1,83 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kyma-incubator/hydroform/function/pkg/client"
+	mockclient "github.com/kyma-incubator/hydroform/function/pkg/client/automock"
+	"github.com/kyma-incubator/hydroform/function/pkg/workspace"
+	"github.com/pkg/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func Test_handleSync_malformedBody(t *testing.T) {
+	s := New(nil, nil, NewMemoryStore())
+	req := httptest.NewRequest(http.MethodPost, "/v1/functions/test-ns/test-fn:sync", bytes.NewReader([]byte("not json")))
+	rec := httptest.NewRecorder()
+
+	s.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func Test_handleSync_malformedPath(t *testing.T) {
+	s := New(nil, nil, NewMemoryStore())
+	req := httptest.NewRequest(http.MethodPost, "/v1/functions/test-fn:sync", nil)
+	rec := httptest.NewRecorder()
+
+	s.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+// Test_handleSync_propagatesWorkspaceError reuses the "getting triggers as
+// unstructured list should fail" failure path already proven in
+// workspace_test.go's Test_Synchronise, to check handleSync surfaces a
+// workspace.Synchronise error as a 500 rather than swallowing it.
+func Test_handleSync_propagatesWorkspaceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	name := "test-fn"
+	namespace := "test-ns"
+
+	mock := mockclient.NewMockClient(ctrl)
+	mock.EXPECT().
+		Get(gomock.Any(), name, v1.GetOptions{}).
+		Return(&unstructured.Unstructured{Object: map[string]interface{}{"test": "test"}}, nil).
+		Times(1)
+	mock.EXPECT().
+		List(gomock.Any(), v1.ListOptions{LabelSelector: "ownerID="}).
+		Return(&unstructured.UnstructuredList{}, errors.New("the error")).
+		Times(1)
+
+	build := func(_ string, _ schema.GroupVersionResource) client.Client {
+		return mock
+	}
+
+	s := New(build, nil, NewMemoryStore())
+
+	body, err := json.Marshal(map[string]interface{}{"cfg": workspace.Cfg{Name: name, Namespace: namespace}})
+	if err != nil {
+		t.Fatalf("marshalling request body: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/v1/functions/"+namespace+"/"+name+":sync", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	s.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d, body = %s", rec.Code, http.StatusInternalServerError, rec.Body.String())
+	}
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"time"
+
+	"github.com/kyma-incubator/hydroform/function/pkg/client"
+)
+
+// OperationStatus is the lifecycle state of a long-running sync/apply
+// operation tracked by a Store.
+type OperationStatus string
+
+const (
+	OperationPending   OperationStatus = "pending"
+	OperationRunning   OperationStatus = "running"
+	OperationSucceeded OperationStatus = "succeeded"
+	OperationFailed    OperationStatus = "failed"
+)
+
+// Operation is the persisted record a client polls to resume watching an
+// apply after a disconnect, keyed by the request ID it was submitted with.
+type Operation struct {
+	RequestID string
+	Namespace string
+	Name      string
+	Status    OperationStatus
+	Entries   []client.PostStatusEntry
+	Error     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,53 @@
+package workspace
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+
+	"github.com/kyma-incubator/hydroform/function/pkg/client"
+)
+
+// Initialize renders cfg into dirPath through writerProvider. If cfg sets
+// a Source, it's resolved and validated through the same provider
+// registry RegisterProvider wires up (git/GitHub/GitLab/Bitbucket/
+// archive/OCI/inline), rather than initialize switching on Source.Type
+// itself. Exported entry point synchronise/the CLI commands use
+// internally through initialize; pkg/server calls it directly so it
+// doesn't need to live in this package to drive the same render path.
+func Initialize(cfg Cfg, dirPath string, writerProvider WriterProvider) error {
+	if cfg.Source.Type != "" {
+		provider, err := resolveProvider(cfg.Source)
+		if err != nil {
+			return err
+		}
+		if err := provider.Validate(cfg); err != nil {
+			return err
+		}
+	}
+	return initialize(cfg, dirPath, writerProvider)
+}
+
+// Synchronise pulls a Function's current spec and triggers from the
+// cluster (via build), then, if cfg sets a Source, resolves it through the
+// provider registry to fetch and materialize the function's actual code
+// into outputPath through writerProvider. Exported counterpart of
+// synchronise for pkg/server.
+func Synchronise(ctx context.Context, cfg Cfg, outputPath string, build client.Build, writerProvider WriterProvider) error {
+	if cfg.Source.Type == "" {
+		return synchronise(ctx, cfg, outputPath, build, writerProvider)
+	}
+	provider, err := resolveProvider(cfg.Source)
+	if err != nil {
+		return err
+	}
+	if err := provider.Validate(cfg); err != nil {
+		return err
+	}
+	if err := synchronise(ctx, cfg, outputPath, build, writerProvider); err != nil {
+		return err
+	}
+	return provider.Materialize(ctx, nil, func(path string) (io.Writer, Cancel, error) {
+		return writerProvider(filepath.Join(outputPath, path))
+	})
+}
@@ -0,0 +1,60 @@
+package workspace
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/kyma-incubator/hydroform/function/pkg/resources/types"
+)
+
+func Test_Initialize_resolvesSourceProvider(t *testing.T) {
+	writerProvider := func(path string) (io.Writer, Cancel, error) {
+		return &bytes.Buffer{}, nil, nil
+	}
+
+	tests := []struct {
+		name    string
+		cfg     Cfg
+		wantErr bool
+	}{
+		{
+			name: "no source configured skips provider resolution",
+			cfg: Cfg{
+				Runtime: types.Python38,
+			},
+			wantErr: false,
+		},
+		{
+			name: "inline source without a path fails validation",
+			cfg: Cfg{
+				Runtime: types.Python38,
+				Source:  Source{Type: SourceTypeInline},
+			},
+			wantErr: true,
+		},
+		{
+			name: "inline source with a path validates",
+			cfg: Cfg{
+				Runtime: types.Python38,
+				Source:  Source{Type: SourceTypeInline, SourceInline: SourceInline{SourcePath: "./testdir/inline"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unregistered source type fails",
+			cfg: Cfg{
+				Runtime: types.Python38,
+				Source:  Source{Type: SourceType("unknown")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Initialize(tt.cfg, "", writerProvider); (err != nil) != tt.wantErr {
+				t.Errorf("Initialize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
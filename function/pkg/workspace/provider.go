@@ -0,0 +1,75 @@
+package workspace
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// SourceTree is the raw result of a SourceProvider.Fetch call: a flat map of
+// file paths (relative to the source root) to their contents, before any
+// runtime-specific templating is applied.
+type SourceTree struct {
+	Files map[string][]byte
+}
+
+// SourceProvider abstracts fetching, validating and materializing a
+// function's source code for a single hosting backend (plain git, GitHub,
+// GitLab, Bitbucket, a local archive, ...). synchronise and initialize
+// resolve the provider for a given Cfg through the registry rather than
+// switching on Source.Type themselves, so adding a backend is a
+// RegisterProvider call away from those two call sites.
+type SourceProvider interface {
+	// Fetch pulls the source tree from the backend, using whatever Auth the
+	// provider was constructed with.
+	Fetch(ctx context.Context) (SourceTree, error)
+	// Validate checks that cfg carries everything this provider needs
+	// (URL, reference, credentials, ...) before Fetch is attempted.
+	Validate(cfg Cfg) error
+	// Materialize writes the tree fetched by Fetch into ws using
+	// writerProvider, the same sink build uses for templated files. ctx
+	// carries the caller's timeout/cancellation through to that Fetch.
+	Materialize(ctx context.Context, ws workspace, writerProvider WriterProvider) error
+}
+
+// ProviderFactory builds the SourceProvider registered for a SourceType.
+type ProviderFactory func(Source) (SourceProvider, error)
+
+var providerRegistry = map[SourceType]ProviderFactory{}
+
+// RegisterProvider wires factory under source type t. Providers call this
+// from an init() in their own file, so registering a new backend never
+// touches synchronise or initialize.
+func RegisterProvider(t SourceType, factory ProviderFactory) {
+	providerRegistry[t] = factory
+}
+
+var errUnknownSourceType = errors.New("no source provider registered for type")
+
+// resolveProvider looks up the SourceProvider registered for src.Type.
+func resolveProvider(src Source) (SourceProvider, error) {
+	factory, ok := providerRegistry[src.Type]
+	if !ok {
+		return nil, errors.Wrapf(errUnknownSourceType, "%s", src.Type)
+	}
+	return factory(src)
+}
+
+// writeSourceTree writes every file in tree out through writerProvider. It's
+// shared by the provider Materialize implementations since none of them
+// need anything backend-specific once the tree has been fetched.
+func writeSourceTree(tree SourceTree, writerProvider WriterProvider) error {
+	for path, content := range tree.Files {
+		w, cancel, err := writerProvider(path)
+		if err != nil {
+			return err
+		}
+		if cancel != nil {
+			defer cancel()
+		}
+		if _, err := w.Write(content); err != nil {
+			return errors.Wrapf(err, "while writing %s", path)
+		}
+	}
+	return nil
+}
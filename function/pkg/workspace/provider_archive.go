@@ -0,0 +1,71 @@
+package workspace
+
+import (
+	"archive/zip"
+	"context"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+)
+
+// SourceTypeArchive fetches function source from a local tar/zip archive
+// rather than a git remote, for offline or air-gapped builds.
+const SourceTypeArchive SourceType = "archive"
+
+// SourceArchive points at a local archive containing a function's source.
+type SourceArchive struct {
+	Path string
+}
+
+func init() {
+	RegisterProvider(SourceTypeArchive, func(src Source) (SourceProvider, error) {
+		return &archiveProvider{source: src.SourceArchive}, nil
+	})
+}
+
+// archiveProvider reads function source out of a local zip archive. It
+// needs no Auth since the archive is already on disk.
+type archiveProvider struct {
+	source SourceArchive
+}
+
+func (p *archiveProvider) Validate(cfg Cfg) error {
+	if p.source.Path == "" {
+		return errors.New("archive source requires a path")
+	}
+	return nil
+}
+
+func (p *archiveProvider) Fetch(ctx context.Context) (SourceTree, error) {
+	r, err := zip.OpenReader(p.source.Path)
+	if err != nil {
+		return SourceTree{}, errors.Wrapf(err, "opening archive %s", p.source.Path)
+	}
+	defer r.Close()
+
+	tree := SourceTree{Files: map[string][]byte{}}
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return SourceTree{}, errors.Wrapf(err, "reading %s from archive", f.Name)
+		}
+		content, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return SourceTree{}, errors.Wrapf(err, "reading %s from archive", f.Name)
+		}
+		tree.Files[f.Name] = content
+	}
+	return tree, nil
+}
+
+func (p *archiveProvider) Materialize(ctx context.Context, ws workspace, writerProvider WriterProvider) error {
+	tree, err := p.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+	return writeSourceTree(tree, writerProvider)
+}
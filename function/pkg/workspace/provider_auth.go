@@ -0,0 +1,37 @@
+package workspace
+
+// AuthType enumerates the credential kinds a git-backed SourceProvider can
+// carry. Providers that don't talk to a remote (inline, local archive)
+// leave it at AuthTypeNone.
+type AuthType string
+
+const (
+	AuthTypeNone  AuthType = ""
+	AuthTypeSSH   AuthType = "ssh"
+	AuthTypePAT   AuthType = "pat"
+	AuthTypeOAuth AuthType = "oauth"
+	AuthTypeBasic AuthType = "basic"
+)
+
+// Auth carries the credentials a git SourceProvider needs to reach a
+// private repository. Only the field matching Type is expected to be set;
+// the rest are zero values. Source carries an Auth field alongside
+// SourceGit/SourceInline/etc., so gitProvider and its GitHub/GitLab/
+// Bitbucket specializations can read src.Auth straight out of the Cfg a
+// caller submits, with no separate credential channel.
+type Auth struct {
+	Type AuthType
+
+	// SSHKeyPath points at a private key on disk. Used when Type is AuthTypeSSH.
+	SSHKeyPath string
+
+	// PAT is a personal access token. Used when Type is AuthTypePAT.
+	PAT string
+
+	// OAuthToken is used when Type is AuthTypeOAuth.
+	OAuthToken string
+
+	// BasicAuthSecretRef names the cluster secret holding a username and
+	// password. Used when Type is AuthTypeBasic.
+	BasicAuthSecretRef string
+}
@@ -0,0 +1,141 @@
+package workspace
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+func init() {
+	RegisterProvider(SourceTypeGit, func(src Source) (SourceProvider, error) {
+		return &gitProvider{source: src.SourceGit, auth: src.Auth}, nil
+	})
+}
+
+// gitProvider fetches function source from a plain, self-hosted git
+// remote. The GitHub, GitLab and Bitbucket providers embed it and layer
+// host-specific Validate checks on top, since the fetch itself is the same
+// clone-and-checkout regardless of who's hosting the repository.
+type gitProvider struct {
+	source SourceGit
+	auth   Auth
+}
+
+func (p *gitProvider) Validate(cfg Cfg) error {
+	if p.source.URL == "" {
+		return errors.New("git source requires a URL")
+	}
+	if p.source.Reference == "" {
+		return errors.New("git source requires a reference")
+	}
+	if p.auth.Type == AuthTypeBasic {
+		return errors.New("basic auth secret refs are not yet resolvable by gitProvider; use ssh, pat or oauth")
+	}
+	return nil
+}
+
+func (p *gitProvider) Fetch(ctx context.Context) (SourceTree, error) {
+	return cloneSourceTree(ctx, p.source, p.auth)
+}
+
+func (p *gitProvider) Materialize(ctx context.Context, ws workspace, writerProvider WriterProvider) error {
+	tree, err := p.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+	return writeSourceTree(tree, writerProvider)
+}
+
+// cloneSourceTree clones source.URL at source.Reference/source.BaseDir
+// using the supplied credentials, by shelling out to the system git
+// binary rather than vendoring a git implementation. It's the single
+// choke point every git-based provider routes through, so auth handling
+// only needs to be correct in one place.
+//
+// AuthTypeBasic isn't handled here: resolving a secret ref needs a
+// cluster client, which gitProvider isn't given. Callers must reject it
+// in Validate (as gitProvider.Validate does) before Fetch is reached.
+func cloneSourceTree(ctx context.Context, source SourceGit, auth Auth) (SourceTree, error) {
+	dir, err := ioutil.TempDir("", "hydroform-source-")
+	if err != nil {
+		return SourceTree{}, errors.Wrap(err, "creating clone directory")
+	}
+	defer os.RemoveAll(dir)
+
+	cmd := exec.CommandContext(ctx, "git", "clone", "--depth", "1", "--branch", source.Reference, authenticatedURL(source.URL, auth), dir)
+	cmd.Env = append(os.Environ(), gitEnv(auth)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return SourceTree{}, errors.Wrapf(err, "git clone failed: %s", out)
+	}
+
+	return readSourceTree(filepath.Join(dir, source.BaseDir))
+}
+
+// readSourceTree walks root (skipping .git) into a flat SourceTree.
+func readSourceTree(root string) (SourceTree, error) {
+	tree := SourceTree{Files: map[string][]byte{}}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		tree.Files[rel] = content
+		return nil
+	})
+	if err != nil {
+		return SourceTree{}, errors.Wrapf(err, "reading cloned source from %s", root)
+	}
+	return tree, nil
+}
+
+// authenticatedURL embeds a PAT or OAuth token as userinfo on the clone
+// URL, the way GitHub/GitLab/Bitbucket all accept token auth over HTTPS.
+// SSH auth is carried via gitEnv instead, since it's keyed off the
+// GIT_SSH_COMMAND environment rather than the URL.
+func authenticatedURL(rawURL string, auth Auth) string {
+	var user, pass string
+	switch auth.Type {
+	case AuthTypePAT:
+		user, pass = "x-access-token", auth.PAT
+	case AuthTypeOAuth:
+		user, pass = "oauth2", auth.OAuthToken
+	default:
+		return rawURL
+	}
+	if pass == "" {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.User = url.UserPassword(user, pass)
+	return u.String()
+}
+
+// gitEnv returns the environment overrides needed to authenticate the
+// clone, beyond what authenticatedURL can carry on the URL itself.
+func gitEnv(auth Auth) []string {
+	if auth.Type == AuthTypeSSH && auth.SSHKeyPath != "" {
+		return []string{"GIT_SSH_COMMAND=ssh -i " + auth.SSHKeyPath + " -o IdentitiesOnly=yes"}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package workspace
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// New SourceType values for the hosting-specific providers. SourceTypeGit
+// and SourceTypeInline remain as they were; these extend the registry
+// rather than replacing anything.
+const (
+	SourceTypeGitHub    SourceType = "github"
+	SourceTypeGitLab    SourceType = "gitlab"
+	SourceTypeBitbucket SourceType = "bitbucket"
+)
+
+func init() {
+	RegisterProvider(SourceTypeGitHub, func(src Source) (SourceProvider, error) {
+		return &githubProvider{gitProvider{source: src.SourceGit, auth: src.Auth}}, nil
+	})
+	RegisterProvider(SourceTypeGitLab, func(src Source) (SourceProvider, error) {
+		return &gitlabProvider{gitProvider{source: src.SourceGit, auth: src.Auth}}, nil
+	})
+	RegisterProvider(SourceTypeBitbucket, func(src Source) (SourceProvider, error) {
+		return &bitbucketProvider{gitProvider{source: src.SourceGit, auth: src.Auth}}, nil
+	})
+}
+
+// githubProvider is a gitProvider that additionally insists the remote is
+// actually hosted on github.com, so a misconfigured manifest fails in
+// Validate rather than at clone time.
+type githubProvider struct {
+	gitProvider
+}
+
+func (p *githubProvider) Validate(cfg Cfg) error {
+	if err := p.gitProvider.Validate(cfg); err != nil {
+		return err
+	}
+	return requireHost(p.source.URL, "github.com")
+}
+
+// gitlabProvider is a gitProvider with no extra host restriction: unlike
+// githubProvider and bitbucketProvider, which are SaaS-only and can
+// enforce a fixed host, a GitLab source may point at gitlab.com or any
+// self-hosted instance, so SourceGit.URL is trusted as-is.
+type gitlabProvider struct {
+	gitProvider
+}
+
+func (p *gitlabProvider) Validate(cfg Cfg) error {
+	return p.gitProvider.Validate(cfg)
+}
+
+// bitbucketProvider is a gitProvider scoped to bitbucket.org.
+type bitbucketProvider struct {
+	gitProvider
+}
+
+func (p *bitbucketProvider) Validate(cfg Cfg) error {
+	if err := p.gitProvider.Validate(cfg); err != nil {
+		return err
+	}
+	return requireHost(p.source.URL, "bitbucket.org")
+}
+
+// requireHost checks that rawURL's actual host is host itself or a proper
+// subdomain of it, not merely that host appears somewhere in rawURL as a
+// substring (which "https://attacker.example/?x=github.com" or
+// "https://github.com.attacker.example/repo.git" would also satisfy).
+func requireHost(rawURL, host string) error {
+	hostname, err := hostnameOf(rawURL)
+	if err != nil {
+		return errors.Wrapf(err, "source URL %q", rawURL)
+	}
+	if hostname != host && !strings.HasSuffix(hostname, "."+host) {
+		return errors.Errorf("source URL %q is not hosted on %s", rawURL, host)
+	}
+	return nil
+}
+
+// hostnameOf extracts the host from rawURL, supporting both standard
+// scheme://host/... URLs and the scp-like user@host:path syntax `git
+// clone` also accepts for SSH remotes.
+func hostnameOf(rawURL string) (string, error) {
+	if u, err := url.Parse(rawURL); err == nil && u.Hostname() != "" {
+		return u.Hostname(), nil
+	}
+	if at := strings.Index(rawURL, "@"); at >= 0 {
+		rest := rawURL[at+1:]
+		if colon := strings.Index(rest, ":"); colon >= 0 {
+			return rest[:colon], nil
+		}
+	}
+	return "", errors.New("could not determine host")
+}
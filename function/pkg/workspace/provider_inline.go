@@ -0,0 +1,38 @@
+package workspace
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+func init() {
+	RegisterProvider(SourceTypeInline, func(src Source) (SourceProvider, error) {
+		return &inlineProvider{source: src.SourceInline}, nil
+	})
+}
+
+// inlineProvider handles function source that's already inlined into the
+// Cfg (SourceInline's SourcePath/SourceHandlerName/DepsHandlerName) rather
+// than fetched from a remote. There's nothing to pull or write beyond
+// what build/initialize already do with those fields, so Fetch and
+// Materialize are no-ops; the provider only exists so SourceTypeInline
+// resolves through the same registry every other source type does.
+type inlineProvider struct {
+	source SourceInline
+}
+
+func (p *inlineProvider) Validate(cfg Cfg) error {
+	if p.source.SourcePath == "" {
+		return errors.New("inline source requires a source path")
+	}
+	return nil
+}
+
+func (p *inlineProvider) Fetch(ctx context.Context) (SourceTree, error) {
+	return SourceTree{}, nil
+}
+
+func (p *inlineProvider) Materialize(ctx context.Context, ws workspace, writerProvider WriterProvider) error {
+	return nil
+}
@@ -0,0 +1,381 @@
+package workspace
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MediaTypeFunctionArtifact is the custom OCI media type a function
+// workspace (handler + deps + config.yaml, tarred and gzipped into a
+// single layer) is pushed and pulled under.
+const MediaTypeFunctionArtifact = "application/vnd.kyma.function.v1.layer+tar+gzip"
+
+// mediaTypeManifest is the OCI image manifest media type pullSourceTree/
+// pushSourceTree read and write at /v2/<repository>/manifests/<tag>.
+const mediaTypeManifest = "application/vnd.oci.image.manifest.v1+json"
+
+// mediaTypeConfig is the (empty) config blob every manifest needs a
+// descriptor for; function artifacts carry no config of their own.
+const mediaTypeConfig = "application/vnd.kyma.function.v1.config+json"
+
+// SourceTypeOCI fetches function source from a pre-pushed OCI artifact
+// instead of a git remote, giving an immutable, signable distribution
+// format that doesn't depend on a reachable git host at apply time.
+const SourceTypeOCI SourceType = "oci"
+
+// SourceOCI points at the OCI artifact a function's code was pushed to.
+type SourceOCI struct {
+	Reference     string
+	MediaType     string
+	AuthSecretRef string
+}
+
+func init() {
+	RegisterProvider(SourceTypeOCI, func(src Source) (SourceProvider, error) {
+		return &ociProvider{source: src.SourceOCI}, nil
+	})
+}
+
+// ociProvider pulls a function workspace back out of the OCI artifact it
+// was pushed to by Push, and unpacks it through the same WriterProvider
+// every other provider materializes through.
+type ociProvider struct {
+	source SourceOCI
+}
+
+func (p *ociProvider) Validate(cfg Cfg) error {
+	if p.source.Reference == "" {
+		return errors.New("oci source requires a reference")
+	}
+	if p.source.AuthSecretRef != "" {
+		return errors.New("oci auth secret refs are not yet resolvable by ociProvider")
+	}
+	return nil
+}
+
+func (p *ociProvider) Fetch(ctx context.Context) (SourceTree, error) {
+	mediaType := p.source.MediaType
+	if mediaType == "" {
+		mediaType = MediaTypeFunctionArtifact
+	}
+	return pullSourceTree(ctx, p.source.Reference, mediaType)
+}
+
+func (p *ociProvider) Materialize(ctx context.Context, ws workspace, writerProvider WriterProvider) error {
+	tree, err := p.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+	return writeSourceTree(tree, writerProvider)
+}
+
+// Push packs tree as a single layer under MediaTypeFunctionArtifact and
+// pushes it to reference, returning the reference the resulting Function
+// CR's source spec should point at.
+//
+// Push is a standalone registry-transport primitive: nothing in this
+// package calls it, and Initialize and the fromSources/fromRuntime render
+// path do not push a rendered workspace as an OCI artifact.
+func (p *ociProvider) Push(ctx context.Context, tree SourceTree, reference string) (string, error) {
+	return pushSourceTree(ctx, reference, MediaTypeFunctionArtifact, tree)
+}
+
+// ociRef is a parsed "host/repository:tag" OCI reference, e.g.
+// "registry.example.com/functions/my-fn:v1".
+type ociRef struct {
+	host       string
+	repository string
+	tag        string
+}
+
+func parseOCIReference(reference string) (ociRef, error) {
+	invalid := errors.Errorf("%q is not a valid OCI reference, expected host/repository:tag", reference)
+
+	slash := strings.Index(reference, "/")
+	if slash < 0 {
+		return ociRef{}, invalid
+	}
+	host, rest := reference[:slash], reference[slash+1:]
+
+	colon := strings.LastIndex(rest, ":")
+	if colon < 0 {
+		return ociRef{}, invalid
+	}
+	repository, tag := rest[:colon], rest[colon+1:]
+	if repository == "" || tag == "" {
+		return ociRef{}, invalid
+	}
+	return ociRef{host: host, repository: repository, tag: tag}, nil
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+// pullSourceTree fetches the manifest at reference, then the first layer
+// matching mediaType, and untars/ungzips it into a SourceTree. It talks
+// directly to the OCI Distribution HTTP API rather than a registry client
+// library, the same way cloneSourceTree shells out to git instead of
+// vendoring one.
+func pullSourceTree(ctx context.Context, reference, mediaType string) (SourceTree, error) {
+	ref, err := parseOCIReference(reference)
+	if err != nil {
+		return SourceTree{}, err
+	}
+
+	manifest, err := getManifest(ctx, ref)
+	if err != nil {
+		return SourceTree{}, errors.Wrapf(err, "fetching manifest for %s", reference)
+	}
+
+	var layer *ociDescriptor
+	for i := range manifest.Layers {
+		if manifest.Layers[i].MediaType == mediaType {
+			layer = &manifest.Layers[i]
+			break
+		}
+	}
+	if layer == nil {
+		return SourceTree{}, errors.Errorf("no layer of media type %s in manifest for %s", mediaType, reference)
+	}
+
+	blob, err := getBlob(ctx, ref, layer.Digest)
+	if err != nil {
+		return SourceTree{}, errors.Wrapf(err, "fetching layer %s for %s", layer.Digest, reference)
+	}
+
+	files, err := untarGzip(blob)
+	if err != nil {
+		return SourceTree{}, errors.Wrapf(err, "unpacking layer %s for %s", layer.Digest, reference)
+	}
+	return SourceTree{Files: files}, nil
+}
+
+// pushSourceTree tars/gzips tree's files as a single layer under
+// mediaType, pushes the blob, then pushes a manifest referencing it under
+// reference's tag.
+func pushSourceTree(ctx context.Context, reference, mediaType string, tree SourceTree) (string, error) {
+	ref, err := parseOCIReference(reference)
+	if err != nil {
+		return "", err
+	}
+
+	layer, err := tarGzip(tree.Files)
+	if err != nil {
+		return "", errors.Wrap(err, "packing source tree into a layer")
+	}
+	layerDigest := digestOf(layer)
+	if err := pushBlob(ctx, ref, layerDigest, layer); err != nil {
+		return "", errors.Wrapf(err, "pushing layer for %s", reference)
+	}
+
+	config := []byte("{}")
+	configDigest := digestOf(config)
+	if err := pushBlob(ctx, ref, configDigest, config); err != nil {
+		return "", errors.Wrapf(err, "pushing config for %s", reference)
+	}
+
+	manifest := ociManifest{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeManifest,
+		Config:        ociDescriptor{MediaType: mediaTypeConfig, Digest: configDigest, Size: int64(len(config))},
+		Layers:        []ociDescriptor{{MediaType: mediaType, Digest: layerDigest, Size: int64(len(layer))}},
+	}
+	if err := putManifest(ctx, ref, manifest); err != nil {
+		return "", errors.Wrapf(err, "pushing manifest for %s", reference)
+	}
+	return reference, nil
+}
+
+func digestOf(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func registryURL(ref ociRef, path string) string {
+	return fmt.Sprintf("https://%s/v2/%s/%s", ref.host, ref.repository, path)
+}
+
+func getManifest(ctx context.Context, ref ociRef) (ociManifest, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, registryURL(ref, "manifests/"+ref.tag), nil)
+	if err != nil {
+		return ociManifest{}, err
+	}
+	req.Header.Set("Accept", mediaTypeManifest)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return ociManifest{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ociManifest{}, errors.Errorf("registry returned %s for manifest", resp.Status)
+	}
+
+	var manifest ociManifest
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return ociManifest{}, errors.Wrap(err, "decoding manifest")
+	}
+	return manifest, nil
+}
+
+func getBlob(ctx context.Context, ref ociRef, digest string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, registryURL(ref, "blobs/"+digest), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("registry returned %s for blob %s", resp.Status, digest)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// pushBlob uploads content under digest using the registry's monolithic
+// upload flow: start an upload session, then PUT the whole blob to the
+// location it hands back with the digest as a query parameter.
+func pushBlob(ctx context.Context, ref ociRef, digest string, content []byte) error {
+	startReq, err := http.NewRequestWithContext(ctx, http.MethodPost, registryURL(ref, "blobs/uploads/"), nil)
+	if err != nil {
+		return err
+	}
+	startResp, err := http.DefaultClient.Do(startReq)
+	if err != nil {
+		return err
+	}
+	startResp.Body.Close()
+	if startResp.StatusCode != http.StatusAccepted {
+		return errors.Errorf("registry returned %s starting blob upload", startResp.Status)
+	}
+
+	location := startResp.Header.Get("Location")
+	if location == "" {
+		return errors.New("registry did not return an upload location")
+	}
+	// The registry may hand back a Location relative to itself.
+	resolved, err := startReq.URL.Parse(location)
+	if err != nil {
+		return errors.Wrapf(err, "parsing upload location %q", location)
+	}
+	location = resolved.String()
+	if strings.Contains(location, "?") {
+		location += "&digest=" + digest
+	} else {
+		location += "?digest=" + digest
+	}
+
+	putReq, err := http.NewRequestWithContext(ctx, http.MethodPut, location, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	putReq.Header.Set("Content-Type", "application/octet-stream")
+	putResp, err := http.DefaultClient.Do(putReq)
+	if err != nil {
+		return err
+	}
+	defer putResp.Body.Close()
+	if putResp.StatusCode != http.StatusCreated {
+		return errors.Errorf("registry returned %s committing blob %s", putResp.Status, digest)
+	}
+	return nil
+}
+
+func putManifest(ctx context.Context, ref ociRef, manifest ociManifest) error {
+	body, err := json.Marshal(manifest)
+	if err != nil {
+		return errors.Wrap(err, "marshalling manifest")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, registryURL(ref, "manifests/"+ref.tag), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mediaTypeManifest)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return errors.Errorf("registry returned %s pushing manifest", resp.Status)
+	}
+	return nil
+}
+
+func tarGzip(files map[string][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(content)), Mode: 0644}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func untarGzip(content []byte) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[header.Name] = content
+	}
+	return files, nil
+}
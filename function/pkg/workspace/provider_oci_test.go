@@ -0,0 +1,175 @@
+package workspace
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_ociProvider(t *testing.T) {
+	tests := []struct {
+		name            string
+		source          Source
+		wantResolveErr  bool
+		wantValidateErr bool
+	}{
+		{
+			name:            "happy path",
+			source:          Source{Type: SourceTypeOCI, SourceOCI: SourceOCI{Reference: "registry.example.com/functions/my-fn:v1"}},
+			wantResolveErr:  false,
+			wantValidateErr: false,
+		},
+		{
+			name:            "missing reference",
+			source:          Source{Type: SourceTypeOCI},
+			wantResolveErr:  false,
+			wantValidateErr: true,
+		},
+		{
+			name:            "auth secret ref not yet supported",
+			source:          Source{Type: SourceTypeOCI, SourceOCI: SourceOCI{Reference: "registry.example.com/functions/my-fn:v1", AuthSecretRef: "my-fn-registry-creds"}},
+			wantResolveErr:  false,
+			wantValidateErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := resolveProvider(tt.source)
+			if (err != nil) != tt.wantResolveErr {
+				t.Fatalf("resolveProvider() error = %v, wantErr %v", err, tt.wantResolveErr)
+			}
+			if err != nil {
+				return
+			}
+			if err := provider.Validate(Cfg{}); (err != nil) != tt.wantValidateErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantValidateErr)
+			}
+		})
+	}
+}
+
+func Test_parseOCIReference(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     string
+		want    ociRef
+		wantErr bool
+	}{
+		{
+			name: "registry host with nested repository path",
+			ref:  "registry.example.com/functions/my-fn:v1",
+			want: ociRef{host: "registry.example.com", repository: "functions/my-fn", tag: "v1"},
+		},
+		{
+			name:    "missing tag",
+			ref:     "registry.example.com/functions/my-fn",
+			wantErr: true,
+		},
+		{
+			name:    "missing repository",
+			ref:     "registry.example.com",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOCIReference(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOCIReference() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("parseOCIReference() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_tarGzipRoundTrip(t *testing.T) {
+	files := map[string][]byte{
+		"handler.js":   []byte("module.exports = (event, context) => {}"),
+		"package.json": []byte(`{"name":"my-fn"}`),
+	}
+
+	layer, err := tarGzip(files)
+	if err != nil {
+		t.Fatalf("tarGzip() error = %v", err)
+	}
+	got, err := untarGzip(layer)
+	if err != nil {
+		t.Fatalf("untarGzip() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, files) {
+		t.Errorf("untarGzip(tarGzip(files)) = %v, want %v", got, files)
+	}
+}
+
+// Test_pushSourceTree_pullSourceTree drives pushSourceTree and
+// pullSourceTree against an httptest server implementing just enough of
+// the OCI Distribution API (blob upload, manifest PUT/GET, blob GET) to
+// exercise the real registry transport end to end.
+func Test_pushSourceTree_pullSourceTree(t *testing.T) {
+	blobs := map[string][]byte{}
+	var manifest []byte
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/functions/my-fn/blobs/uploads/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/v2/functions/my-fn/blobs/uploads/1")
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/v2/functions/my-fn/blobs/uploads/1", func(w http.ResponseWriter, r *http.Request) {
+		digest := r.URL.Query().Get("digest")
+		body, _ := ioutil.ReadAll(r.Body)
+		blobs[digest] = body
+		w.WriteHeader(http.StatusCreated)
+	})
+	mux.HandleFunc("/v2/functions/my-fn/manifests/v1", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := ioutil.ReadAll(r.Body)
+			manifest = body
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodGet:
+			w.Header().Set("Content-Type", mediaTypeManifest)
+			_, _ = w.Write(manifest)
+		}
+	})
+	mux.HandleFunc("/v2/functions/my-fn/blobs/", func(w http.ResponseWriter, r *http.Request) {
+		digest := r.URL.Path[len("/v2/functions/my-fn/blobs/"):]
+		content, ok := blobs[digest]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(content)
+	})
+
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	previousClient := http.DefaultClient
+	http.DefaultClient = server.Client()
+	defer func() { http.DefaultClient = previousClient }()
+
+	host := server.Listener.Addr().String()
+	reference := host + "/functions/my-fn:v1"
+
+	files := map[string][]byte{"handler.js": []byte("module.exports = (event, context) => {}")}
+	pushed, err := pushSourceTree(context.Background(), reference, MediaTypeFunctionArtifact, SourceTree{Files: files})
+	if err != nil {
+		t.Fatalf("pushSourceTree() error = %v", err)
+	}
+	if pushed != reference {
+		t.Errorf("pushSourceTree() = %v, want %v", pushed, reference)
+	}
+
+	tree, err := pullSourceTree(context.Background(), reference, MediaTypeFunctionArtifact)
+	if err != nil {
+		t.Fatalf("pullSourceTree() error = %v", err)
+	}
+	if !reflect.DeepEqual(tree.Files, files) {
+		t.Errorf("pullSourceTree() = %v, want %v", tree.Files, files)
+	}
+}
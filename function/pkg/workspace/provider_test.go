@@ -0,0 +1,110 @@
+package workspace
+
+import "testing"
+
+func Test_resolveProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  Source
+		wantErr bool
+	}{
+		{
+			name:    "git",
+			source:  Source{Type: SourceTypeGit, SourceGit: SourceGit{URL: "https://example.com/repo.git", Reference: "main"}},
+			wantErr: false,
+		},
+		{
+			name:    "github",
+			source:  Source{Type: SourceTypeGitHub, SourceGit: SourceGit{URL: "https://github.com/kyma-incubator/hydroform.git", Reference: "main"}},
+			wantErr: false,
+		},
+		{
+			name:    "gitlab",
+			source:  Source{Type: SourceTypeGitLab, SourceGit: SourceGit{URL: "https://gitlab.com/example/repo.git", Reference: "main"}},
+			wantErr: false,
+		},
+		{
+			name:    "bitbucket",
+			source:  Source{Type: SourceTypeBitbucket, SourceGit: SourceGit{URL: "https://bitbucket.org/example/repo.git", Reference: "main"}},
+			wantErr: false,
+		},
+		{
+			name:    "archive",
+			source:  Source{Type: SourceTypeArchive, SourceArchive: SourceArchive{Path: "./testdir/source.zip"}},
+			wantErr: false,
+		},
+		{
+			name:    "unregistered type",
+			source:  Source{Type: SourceType("unknown")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolveProvider(tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolveProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_providerValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  Source
+		wantErr bool
+	}{
+		{
+			name:    "github wrong host",
+			source:  Source{Type: SourceTypeGitHub, SourceGit: SourceGit{URL: "https://gitlab.com/example/repo.git", Reference: "main"}},
+			wantErr: true,
+		},
+		{
+			name:    "github missing reference",
+			source:  Source{Type: SourceTypeGitHub, SourceGit: SourceGit{URL: "https://github.com/example/repo.git"}},
+			wantErr: true,
+		},
+		{
+			name:    "github happy path",
+			source:  Source{Type: SourceTypeGitHub, SourceGit: SourceGit{URL: "https://github.com/example/repo.git", Reference: "main"}},
+			wantErr: false,
+		},
+		{
+			name:    "github host substring in query string is not a match",
+			source:  Source{Type: SourceTypeGitHub, SourceGit: SourceGit{URL: "https://attacker.example/repo.git?x=github.com", Reference: "main"}},
+			wantErr: true,
+		},
+		{
+			name:    "github host substring as subdomain suffix is not a match",
+			source:  Source{Type: SourceTypeGitHub, SourceGit: SourceGit{URL: "https://github.com.attacker.example/repo.git", Reference: "main"}},
+			wantErr: true,
+		},
+		{
+			name:    "github ssh remote host is matched exactly",
+			source:  Source{Type: SourceTypeGitHub, SourceGit: SourceGit{URL: "git@github.com:example/repo.git", Reference: "main"}},
+			wantErr: false,
+		},
+		{
+			name:    "bitbucket host substring in path is not a match",
+			source:  Source{Type: SourceTypeBitbucket, SourceGit: SourceGit{URL: "https://attacker.example/bitbucket.org/repo.git", Reference: "main"}},
+			wantErr: true,
+		},
+		{
+			name:    "archive missing path",
+			source:  Source{Type: SourceTypeArchive},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := resolveProvider(tt.source)
+			if err != nil {
+				t.Fatalf("resolveProvider() error = %v", err)
+			}
+			if err := provider.Validate(Cfg{}); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -256,12 +256,7 @@ func Test_Synchronise(t *testing.T) {
 						},
 					},
 				},
-				build: func() client.Build {
-					c := inlineClient(ctrl, name, namespace)
-					return func(_ string, _ schema.GroupVersionResource) client.Client {
-						return c
-					}
-				}(),
+				build: buildFromSourceClient(SourceTypeInline, ctrl, name, namespace),
 			},
 			wantErr: false,
 		},
@@ -292,12 +287,7 @@ func Test_Synchronise(t *testing.T) {
 						},
 					},
 				},
-				build: func() client.Build {
-					c := gitClient(ctrl, name, namespace)
-					return func(_ string, _ schema.GroupVersionResource) client.Client {
-						return c
-					}
-				}(),
+				build: buildFromSourceClient(SourceTypeGit, ctrl, name, namespace),
 			},
 			wantErr: false,
 		},
@@ -309,10 +299,31 @@ func Test_Synchronise(t *testing.T) {
 				t.Errorf("Synchronise() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.args.cfg.Source.Type == "" {
+				return
+			}
+			if _, err := resolveProvider(tt.args.cfg.Source); err != nil {
+				t.Errorf("resolveProvider(%s) error = %v, want nil", tt.args.cfg.Source.Type, err)
+			}
 		})
 	}
 }
 
+// sourceClients maps each SourceType exercised by Test_Synchronise to the
+// mock client.Client builder for it, so adding a source/provider to the
+// test table is a map entry rather than a bespoke build func per case.
+var sourceClients = map[SourceType]func(ctrl *gomock.Controller, name, namespace string) client.Client{
+	SourceTypeInline: inlineClient,
+	SourceTypeGit:    gitClient,
+}
+
+func buildFromSourceClient(sourceType SourceType, ctrl *gomock.Controller, name, namespace string) client.Build {
+	c := sourceClients[sourceType](ctrl, name, namespace)
+	return func(_ string, _ schema.GroupVersionResource) client.Client {
+		return c
+	}
+}
+
 func newStrWriterProvider() WriterProvider {
 	return func(path string) (io.Writer, Cancel, error) {
 		var buffer bytes.Buffer